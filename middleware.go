@@ -0,0 +1,75 @@
+package veryFastRouter
+
+//===========[STRUCTS]====================================================================================================
+
+//Middleware wraps a HandlerFunc to add behaviour that should run before and/or after it, e.g.
+//logging, auth or recovering from panics
+type Middleware func(HandlerFunc) HandlerFunc
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//Use appends mw to the router's middleware chain. Middleware registered here is composed around
+//every handler registered through r after this call, and through any router derived from r via
+//Group or With after this call. Handlers already registered, and routers already derived, are
+//not affected
+func (r *HttpRouter) Use(mw ...Middleware) {
+	r.middleware = append(r.middleware, mw...)
+}
+
+//With returns a new router that shares the same route tables and status code handlers as r, but
+//carries its own middleware chain - r's chain followed by mw - and its own prefix. A handler
+//registered on the returned router goes into those shared route tables, so r serves it too, but
+//r's own middleware chain and prefix are left unchanged
+func (r *HttpRouter) With(mw ...Middleware) *HttpRouter {
+	chain := make([]Middleware, 0, len(r.middleware)+len(mw))
+	chain = append(chain, r.middleware...)
+	chain = append(chain, mw...)
+
+	return &HttpRouter{
+		staticRoutes:           r.staticRoutes,
+		routeTrie:              r.routeTrie,
+		httpStatusCodeHandlers: r.httpStatusCodeHandlers,
+		prefix:                 r.prefix,
+		middleware:             chain,
+		HandleHEAD:             r.HandleHEAD,
+		HandleOPTIONS:          r.HandleOPTIONS,
+		RedirectTrailingSlash:  r.RedirectTrailingSlash,
+		RedirectFixedPath:      r.RedirectFixedPath,
+	}
+}
+
+//Group calls fn with a sub-router whose patterns are all prefixed with prefix, e.g.
+//r.Group("/v1", func(v1 *HttpRouter) { v1.HandleFunc("/users", ...) }) registers "/v1/users"
+func (r *HttpRouter) Group(prefix string, fn func(*HttpRouter)) {
+	sub := r.With()
+	sub.prefix = joinPattern(r.prefix, prefix)
+
+	fn(sub)
+}
+
+//joinPattern prefixes pattern with prefix without introducing a double "/"
+func joinPattern(prefix, pattern string) string {
+	if prefix == "" {
+		return pattern
+	}
+
+	if pattern == "" {
+		return prefix
+	}
+
+	if prefix[len(prefix)-1] == '/' && pattern[0] == '/' {
+		return prefix + pattern[1:]
+	}
+
+	return prefix + pattern
+}
+
+//compose wraps handler with mw, applying mw[0] as the outermost layer so that middleware runs
+//in the order it was registered
+func compose(handler HandlerFunc, mw []Middleware) HandlerFunc {
+	for i := len(mw) - 1; i >= 0; i-- {
+		handler = mw[i](handler)
+	}
+
+	return handler
+}
@@ -0,0 +1,54 @@
+package veryFastRouter
+
+import "context"
+
+//===========[STRUCTS]====================================================================================================
+
+//contextKey is an unexported type used to avoid collisions with context keys defined in other packages
+type contextKey int
+
+//paramsContextKey is the key under which the matched route's Params are stored on the request context
+const paramsContextKey contextKey = 0
+
+//maxParams bounds how many named parameters a single route can capture. This is independent of
+//bufferSize (the total segment cap), since only ":name"/"*name" segments count against it
+const maxParams = 16
+
+//Params holds the named path parameters captured while matching a route, in a fixed-size array
+//capped at maxParams entries. Note that this still escapes to the heap in practice, since
+//findRoute and ServeHTTP store it behind a pointer on the request context
+type Params struct {
+	count  int
+	names  [maxParams]string
+	values [maxParams]string
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//ByName returns the value captured for the named path parameter, or an empty string if it wasn't matched
+func (p *Params) ByName(name string) string {
+	for i := 0; i < p.count; i++ {
+		if p.names[i] == name {
+			return p.values[i]
+		}
+	}
+
+	return ""
+}
+
+//add records a captured (name, value) pair. Captures beyond maxParams are silently dropped
+func (p *Params) add(name, value string) {
+	if p.count >= maxParams {
+		return
+	}
+
+	p.names[p.count] = name
+	p.values[p.count] = value
+	p.count++
+}
+
+//ParamsFromContext retrieves the Params stored on ctx by the router, or nil if none are present
+func ParamsFromContext(ctx context.Context) *Params {
+	params, _ := ctx.Value(paramsContextKey).(*Params)
+	return params
+}
@@ -0,0 +1,91 @@
+package veryFastRouter
+
+import (
+	"path"
+	"strings"
+)
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//redirectTarget looks for a registered route reachable from p by toggling its trailing slash
+//and/or cleaning and case-folding it, returning the canonical path to redirect to. It returns
+//false when no such route exists, or when the canonical path is identical to p
+func (r *HttpRouter) redirectTarget(p string) (string, bool) {
+	if r.RedirectTrailingSlash {
+		toggled := toggleTrailingSlash(p)
+		if route, _ := r.findRoute(toggled); route != nil && toggled != p {
+			return toggled, true
+		}
+	}
+
+	if r.RedirectFixedPath {
+		cleaned := cleanPath(p)
+
+		if route, _ := r.findRoute(cleaned); route != nil && cleaned != p {
+			return cleaned, true
+		}
+
+		if canonical, ok := r.findCaseInsensitiveMatch(cleaned); ok && canonical != p {
+			return canonical, true
+		}
+	}
+
+	return "", false
+}
+
+//findCaseInsensitiveMatch looks for a route matching p case-insensitively, returning the route's
+//properly-cased canonical path. If more than one registered pattern folds to the same path, the
+//lexicographically smallest is returned, so the result is deterministic across calls
+func (r *HttpRouter) findCaseInsensitiveMatch(p string) (string, bool) {
+	match, found := "", false
+
+	for registered := range r.staticRoutes {
+		if !strings.EqualFold(registered, p) {
+			continue
+		}
+
+		if !found || registered < match {
+			match, found = registered, true
+		}
+	}
+
+	if found {
+		return match, true
+	}
+
+	pd := &pathDetails{}
+
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i] != 47 {
+			continue
+		}
+
+		pd.segments[pd.count] = p[i:]
+		p = p[:i]
+		pd.count++
+	}
+
+	return r.routeTrie.findCaseInsensitive(pd, pd.count-1)
+}
+
+//toggleTrailingSlash returns p with its trailing "/" removed if present, or appends one otherwise
+func toggleTrailingSlash(p string) string {
+	if len(p) > 1 && p[len(p)-1] == '/' {
+		return p[:len(p)-1]
+	}
+
+	return p + "/"
+}
+
+//cleanPath collapses ".", ".." and duplicate "/" in p, returning an absolute, cleaned path
+func cleanPath(p string) string {
+	if p == "" {
+		return "/"
+	}
+
+	if p[0] != '/' {
+		p = "/" + p
+	}
+
+	return path.Clean(p)
+}
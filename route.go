@@ -0,0 +1,38 @@
+package veryFastRouter
+
+import (
+	"sort"
+	"strings"
+)
+
+//===========[STRUCTS]====================================================================================================
+
+//route holds everything needed to match and serve a single registered pattern
+type route struct {
+	//originalPattern is the pattern as supplied by the caller, trailing slash removed
+	originalPattern string
+
+	//segments holds the parsed pieces of the pattern, including any variable segments
+	segments []segment
+
+	//hasVariables is true when the pattern contains at least one ":name" or "*name" segment
+	hasVariables bool
+
+	//handlers maps each http method this route responds to, to the handler registered for it
+	handlers map[Method]HandlerFunc
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//allowHeader returns the registered methods as a sorted, comma-separated list suitable for the
+//"Allow" response header
+func (r *route) allowHeader() string {
+	methods := make([]string, 0, len(r.handlers))
+	for method := range r.handlers {
+		methods = append(methods, string(method))
+	}
+
+	sort.Strings(methods)
+
+	return strings.Join(methods, ", ")
+}
@@ -1,6 +1,7 @@
 package veryFastRouter
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 )
@@ -19,12 +20,19 @@ type HandlerFunc func(http.ResponseWriter, *http.Request)
 
 //Allowed method definitions
 var (
-	GET  Method = "GET"
-	POST Method = "POST"
+	GET     Method = "GET"
+	POST    Method = "POST"
+	PUT     Method = "PUT"
+	PATCH   Method = "PATCH"
+	DELETE  Method = "DELETE"
+	HEAD    Method = "HEAD"
+	OPTIONS Method = "OPTIONS"
+	CONNECT Method = "CONNECT"
+	TRACE   Method = "TRACE"
 )
 
 //AllMethods slice contains all available methods
-var AllMethods = []Method{GET, POST}
+var AllMethods = []Method{GET, POST, PUT, PATCH, DELETE, HEAD, OPTIONS, CONNECT, TRACE}
 
 //===========[STRUCTS]====================================================================================================
 
@@ -35,7 +43,9 @@ type pathDetails struct {
 
 type segment struct {
 	value      string
+	name       string
 	isVariable bool
+	isCatchAll bool
 	ok         bool
 }
 
@@ -44,11 +54,38 @@ type HttpRouter struct {
 	//staticRoutes store all the routes that do not have variables in them
 	staticRoutes map[string]*route
 
-	//variableRoutes store all the routes that contain variables in them
-	variableRoutes []*route
+	//routeTrie is the root of the radix tree that holds all routes containing ":name"/"*name"
+	//segments, letting findRoute match them without a linear scan
+	routeTrie *trieNode
 
 	//httpStatusCodeHandlers hold all the default/custom handlers to various http status codes
 	httpStatusCodeHandlers httpStatusCodeHandlers
+
+	//prefix is prepended to every pattern registered through this router. Only set on sub-routers
+	//returned by Group/With
+	prefix string
+
+	//middleware is this router's middleware chain, composed around every handler registered
+	//through it
+	middleware []Middleware
+
+	//HandleHEAD, when true (the default), serves a HEAD request with the registered GET handler
+	//if no HEAD handler was registered for the matched route
+	HandleHEAD bool
+
+	//HandleOPTIONS, when true (the default), automatically responds to an OPTIONS request with
+	//an Allow header listing the methods registered for the matched route, unless an OPTIONS
+	//handler was registered explicitly
+	HandleOPTIONS bool
+
+	//RedirectTrailingSlash, when true (the default), retries an unmatched path with its trailing
+	//slash toggled and, on a match, redirects to the canonical path instead of returning 404
+	RedirectTrailingSlash bool
+
+	//RedirectFixedPath, when true (the default), retries an unmatched path after cleaning it
+	//(collapsing "..", "." and duplicate "/") and matching case-insensitively, redirecting to the
+	//canonical path on a hit instead of returning 404
+	RedirectFixedPath bool
 }
 
 //HttpStatusCodeHandler allows you to set up custom handlers for various http status codes,
@@ -77,28 +114,37 @@ func (r *HttpRouter) HttpStatusCodeHandler(statusCode int, handler HandlerFunc)
 //or a custom 405 handler will be invoked. For the handler to response to all methods, you
 //should use in AllMethods that's defined in this module
 func (r *HttpRouter) HandleFunc(pattern string, methods []Method, handler HandlerFunc) {
-	route, err := r.addRoute(pattern)
+	route, err := r.addRoute(joinPattern(r.prefix, pattern))
 	if err != nil {
 		panic(err)
 	}
 
-	route.methods = methods
-	if route.methods == nil || len(route.methods) == 0 {
+	if len(methods) == 0 {
 		panic(fmt.Sprintf("method for pattern \"%s\" are not defined!", pattern))
 	}
 
-	route.handler = handler
-	if route.handler == nil {
+	if handler == nil {
 		panic(fmt.Sprintf("handler for pattern \"%s\" is not defined!", pattern))
 	}
-}
 
-//findRoute returns pointer to route based on path supplied
-func (r *HttpRouter) findRoute(path string) *route {
-	path = processPath(path)
+	wrapped := compose(handler, r.middleware)
 
+	if route.handlers == nil {
+		route.handlers = map[Method]HandlerFunc{}
+	}
+
+	for _, method := range methods {
+		route.handlers[method] = wrapped
+	}
+}
+
+//findRoute returns pointer to route based on path supplied, along with any named parameters captured
+//while matching it. Unlike newRoute, this does not strip a trailing "/" - a request path is matched
+//exactly as received, so a request with a trailing slash that wasn't registered misses here and is
+//left for redirectTarget to resolve (see RedirectTrailingSlash)
+func (r *HttpRouter) findRoute(path string) (*route, *Params) {
 	if router, exist := r.staticRoutes[path]; exist {
-		return router
+		return router, nil
 	}
 
 	pd := &pathDetails{
@@ -119,73 +165,108 @@ func (r *HttpRouter) findRoute(path string) *route {
 		pd.count++
 	}
 
-	for i := 0; i < len(r.variableRoutes); i++ {
-		if !r.variableRoutes[i].compare(pd) {
-			continue
-		}
-
-		return r.variableRoutes[i]
+	params := &Params{}
+	if route := r.routeTrie.find(pd, pd.count-1, params); route != nil {
+		return route, params
 	}
 
-	return nil
+	return nil, nil
 }
 
-//addRoute parses pattern supplied and adds it to the HttpRouter
+//addRoute parses pattern supplied and adds it to the HttpRouter. Calling it again with a pattern
+//that was already registered returns the existing route, so a second method can be registered
+//against it rather than discarding the first
 func (r *HttpRouter) addRoute(pattern string) (*route, error) {
-	route, err := newRoute(pattern)
+	rt, err := newRoute(pattern)
 	if err != nil {
 		return nil, err
 	}
 
-	if !route.hasVariables {
-		r.staticRoutes[route.originalPattern] = route
-		return route, nil
+	if !rt.hasVariables {
+		if existing, exist := r.staticRoutes[rt.originalPattern]; exist {
+			return existing, nil
+		}
+
+		r.staticRoutes[rt.originalPattern] = rt
+		return rt, nil
 	}
 
-	r.variableRoutes = append(r.variableRoutes, route)
-	return route, nil
+	return r.routeTrie.insert(rt), nil
 }
 
 //ServerHTTP serves the requests
 func (r *HttpRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	//Looking for route withing the defined handlers
-	route := r.findRoute(req.URL.Path)
+	route, params := r.findRoute(req.URL.Path)
 
 	//This is where custom 404 handler can be established
 	if route == nil {
+		if target, ok := r.redirectTarget(req.URL.Path); ok {
+			status := http.StatusMovedPermanently
+			if req.Method != string(GET) {
+				status = http.StatusPermanentRedirect
+			}
+
+			if req.URL.RawQuery != "" {
+				target += "?" + req.URL.RawQuery
+			}
+
+			http.Redirect(w, req, target, status)
+			return
+		}
+
 		r.httpStatusCodeHandlers.handlers[http.StatusNotFound](w, req)
 		return
 	}
 
-	//Checks whether the method of the request is allowed for this handler
-	allowedMethod := false
-	for i := 0; i < len(route.methods); i++ {
-		if string(route.methods[i]) != req.Method {
-			continue
-		}
+	//Looking up the handler registered for this method
+	method := Method(req.Method)
+	handler, exist := route.handlers[method]
 
-		allowedMethod = true
+	//Falling back to the GET handler for HEAD requests, as most clients expect
+	if !exist && method == HEAD && r.HandleHEAD {
+		handler, exist = route.handlers[GET]
+	}
 
-		break
+	//Responding to OPTIONS automatically, unless the caller registered their own handler for it
+	if !exist && method == OPTIONS && r.HandleOPTIONS {
+		w.Header().Set("Allow", route.allowHeader())
+		return
 	}
 
-	if !allowedMethod {
+	if !exist {
+		w.Header().Set("Allow", route.allowHeader())
 		r.httpStatusCodeHandlers.handlers[http.StatusMethodNotAllowed](w, req)
 		return
 	}
 
-	route.handler(w, req)
+	if params != nil {
+		req = req.WithContext(context.WithValue(req.Context(), paramsContextKey, params))
+	}
+
+	handler(w, req)
 }
 
 //===========[FUNCTIONALITY]====================================================================================================
 
-//newSegment returns a new segment based on the string supplied
+//newSegment returns a new segment based on the string supplied. A segment is variable when it
+//begins with ":" (e.g. "/:id") and catch-all when it begins with "*" (e.g. "/*filepath")
 func newSegment(seg string) segment {
-	return segment{
-		value:      seg,
-		isVariable: seg[1] == 58,
-		ok:         true,
+	s := segment{
+		value: seg,
+		ok:    true,
 	}
+
+	switch seg[1] {
+	case 58: //':'
+		s.isVariable = true
+		s.name = seg[2:]
+	case 42: //'*'
+		s.isCatchAll = true
+		s.name = seg[2:]
+	}
+
+	return s
 }
 
 //splitPath splits path and returns a slice of its segments
@@ -234,13 +315,23 @@ func newRoute(path string) (*route, error) {
 		segments:        splitPath(path),
 	}
 
-	for _, segment := range r.segments {
-		if !segment.isVariable {
+	//Segments are parsed right-to-left (see splitPath), so a catch-all segment - which must be
+	//the last segment of the pattern - always ends up at index 0
+	for i, segment := range r.segments {
+		if segment.isVariable {
+			r.hasVariables = true
+			continue
+		}
+
+		if !segment.isCatchAll {
 			continue
 		}
 
 		r.hasVariables = true
-		break
+
+		if i != 0 {
+			return nil, fmt.Errorf("catch-all segment \"%s\" must be the last segment of pattern \"%s\"", segment.value, path)
+		}
 	}
 
 	return &r, nil
@@ -250,7 +341,11 @@ func newRoute(path string) (*route, error) {
 func NewRouter() *HttpRouter {
 	return &HttpRouter{
 		staticRoutes:           map[string]*route{},
-		variableRoutes:         []*route{},
+		routeTrie:              &trieNode{},
 		httpStatusCodeHandlers: newCustomHttpCodeHandlers(),
+		HandleHEAD:             true,
+		HandleOPTIONS:          true,
+		RedirectTrailingSlash:  true,
+		RedirectFixedPath:      true,
 	}
 }
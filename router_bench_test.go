@@ -0,0 +1,139 @@
+package veryFastRouter
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+//noopHandler is used by the benchmarks below, where only matching performance is of interest
+func noopHandler(http.ResponseWriter, *http.Request) {}
+
+//newBenchRouter builds a router with n static routes and n parameterised routes, mimicking a
+//realistic API surface with a mix of both
+func newBenchRouter(n int) *HttpRouter {
+	r := NewRouter()
+
+	for i := 0; i < n; i++ {
+		r.HandleFunc(fmt.Sprintf("/api/v1/resource%d", i), AllMethods, noopHandler)
+		r.HandleFunc(fmt.Sprintf("/api/v1/resource%d/:id", i), AllMethods, noopHandler)
+	}
+
+	return r
+}
+
+//BenchmarkFindRoute_Static measures lookup of a static route registered last, which is served
+//from the staticRoutes map before the trie is consulted
+func BenchmarkFindRoute_Static(b *testing.B) {
+	r := newBenchRouter(500)
+	path := "/api/v1/resource499"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.findRoute(path)
+	}
+}
+
+//BenchmarkFindRoute_Param measures lookup of a parameterised route registered last, exercising
+//the trie's param-child traversal instead of a scan over every registered variable route
+func BenchmarkFindRoute_Param(b *testing.B) {
+	r := newBenchRouter(500)
+	path := "/api/v1/resource499/42"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		r.findRoute(path)
+	}
+}
+
+//newBenchPathDetails splits path into a pathDetails the way findRoute does, for feeding directly
+//into a matcher in a benchmark
+func newBenchPathDetails(path string) *pathDetails {
+	pd := &pathDetails{}
+
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i] != 47 {
+			continue
+		}
+
+		pd.segments[pd.count] = path[i:]
+		path = path[:i]
+		pd.count++
+	}
+
+	return pd
+}
+
+//linearCompare reimplements the matching rules route.compare used to apply before this package
+//switched to routeTrie (see chunk0-3): segment-by-segment comparison, with a catch-all - always
+//parsed into segments[0] - matching everything from that point on
+func linearCompare(r *route, pd *pathDetails) bool {
+	segCount := len(r.segments)
+	hasCatchAll := segCount > 0 && r.segments[0].isCatchAll
+
+	staticCount := segCount
+	if hasCatchAll {
+		staticCount--
+	}
+
+	if hasCatchAll {
+		if pd.count < staticCount {
+			return false
+		}
+	} else if pd.count != segCount {
+		return false
+	}
+
+	for i := 0; i < staticCount; i++ {
+		seg := r.segments[segCount-1-i]
+		value := pd.segments[pd.count-1-i]
+
+		if !seg.isVariable && seg.value != value {
+			return false
+		}
+	}
+
+	return true
+}
+
+//linearFindRoute mirrors HttpRouter.findRoute's pre-trie matching: a linear scan over every
+//registered variable route, calling compare on each until one matches
+func linearFindRoute(routes []*route, pd *pathDetails) *route {
+	for i := 0; i < len(routes); i++ {
+		if linearCompare(routes[i], pd) {
+			return routes[i]
+		}
+	}
+
+	return nil
+}
+
+//newBenchLinearRoutes builds the same n parameterised routes as newBenchRouter, but as a plain
+//slice rather than inserted into a trie, for benchmarking against the matcher it replaced
+func newBenchLinearRoutes(n int) []*route {
+	routes := make([]*route, 0, n)
+
+	for i := 0; i < n; i++ {
+		rt, err := newRoute(fmt.Sprintf("/api/v1/resource%d/:id", i))
+		if err != nil {
+			panic(err)
+		}
+
+		routes = append(routes, rt)
+	}
+
+	return routes
+}
+
+//BenchmarkFindRoute_LinearScanBaseline measures the same worst-case lookup as
+//BenchmarkFindRoute_Param - the last of 500 registered parameterised routes - but via the O(N)
+//linear scan routeTrie replaced, to substantiate the trie's speedup over it
+func BenchmarkFindRoute_LinearScanBaseline(b *testing.B) {
+	routes := newBenchLinearRoutes(500)
+	pd := newBenchPathDetails("/api/v1/resource499/42")
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linearFindRoute(routes, pd)
+	}
+}
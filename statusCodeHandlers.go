@@ -0,0 +1,27 @@
+package veryFastRouter
+
+import "net/http"
+
+//===========[STRUCTS]====================================================================================================
+
+//httpStatusCodeHandlers stores the handlers responsible for responding to requests that could not be
+//routed normally, e.g. 404, 405
+type httpStatusCodeHandlers struct {
+	handlers map[int]HandlerFunc
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//newCustomHttpCodeHandlers returns httpStatusCodeHandlers populated with sensible defaults
+func newCustomHttpCodeHandlers() httpStatusCodeHandlers {
+	return httpStatusCodeHandlers{
+		handlers: map[int]HandlerFunc{
+			http.StatusNotFound: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			http.StatusMethodNotAllowed: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			},
+		},
+	}
+}
@@ -0,0 +1,180 @@
+package veryFastRouter
+
+import (
+	"fmt"
+	"strings"
+)
+
+//===========[STRUCTS]====================================================================================================
+
+//trieNode is one node of the radix tree that backs matching of routes containing variable or
+//catch-all segments. A node may have any number of static children (keyed by the literal segment
+//value), at most one param child and at most one catchAll child
+type trieNode struct {
+	//name holds the ":name"/"*name" parameter name this node was reached through. Unused on
+	//static nodes
+	name string
+
+	//static holds the children reached by a literal segment value, e.g. "/users"
+	static map[string]*trieNode
+
+	//param is the child reached by a ":name" segment, if one has been registered at this depth
+	param *trieNode
+
+	//catchAll is the child reached by a "*name" segment, if one has been registered at this depth.
+	//Since a catch-all must be the last segment of a pattern, it is always terminal
+	catchAll *trieNode
+
+	//route is set when a registered pattern ends at this node
+	route *route
+}
+
+//===========[FUNCTIONALITY]====================================================================================================
+
+//insert adds rt into the trie rooted at n and returns the route now stored at that position.
+//If a route was already registered for rt's exact pattern, that existing route is returned
+//instead, so callers can add further methods to it rather than discarding it. rt.segments is
+//stored right-to-left (see splitPath), so it's walked back to front here to insert segments in
+//their original, left-to-right order
+func (n *trieNode) insert(rt *route) *route {
+	cur := n
+
+	for i := len(rt.segments) - 1; i >= 0; i-- {
+		seg := rt.segments[i]
+
+		switch {
+		case seg.isCatchAll:
+			if cur.catchAll == nil {
+				cur.catchAll = &trieNode{name: seg.name}
+			} else if cur.catchAll.name != seg.name {
+				panic(fmt.Sprintf("veryFastRouter: catch-all \"*%s\" conflicts with already registered \"*%s\" for pattern \"%s\"", seg.name, cur.catchAll.name, rt.originalPattern))
+			}
+			cur = cur.catchAll
+
+		case seg.isVariable:
+			if cur.param == nil {
+				cur.param = &trieNode{name: seg.name}
+			} else if cur.param.name != seg.name {
+				panic(fmt.Sprintf("veryFastRouter: param \":%s\" conflicts with already registered \":%s\" for pattern \"%s\"", seg.name, cur.param.name, rt.originalPattern))
+			}
+			cur = cur.param
+
+		default:
+			if cur.static == nil {
+				cur.static = map[string]*trieNode{}
+			}
+
+			child, exist := cur.static[seg.value]
+			if !exist {
+				child = &trieNode{}
+				cur.static[seg.value] = child
+			}
+			cur = child
+		}
+	}
+
+	if cur.route == nil {
+		cur.route = rt
+	}
+
+	return cur.route
+}
+
+//find descends the trie matching pd's segments, preferring a static child, then the param child,
+//then the catchAll child. idx is the index, within pd.segments, of the next segment to match -
+//counting down from pd.count-1 (the first segment of the path) to 0 (the last). Matched param
+//values are recorded into params as each branch is confirmed to lead to a match
+func (n *trieNode) find(pd *pathDetails, idx int, params *Params) *route {
+	if idx < 0 {
+		return n.route
+	}
+
+	value := pd.segments[idx]
+
+	if n.static != nil {
+		if child, exist := n.static[value]; exist {
+			if rt := child.find(pd, idx-1, params); rt != nil {
+				return rt
+			}
+		}
+	}
+
+	if n.param != nil {
+		if rt := n.param.find(pd, idx-1, params); rt != nil {
+			if params != nil {
+				params.add(n.param.name, value[1:])
+			}
+			return rt
+		}
+	}
+
+	if n.catchAll != nil && n.catchAll.route != nil {
+		if params != nil {
+			tail := ""
+			for i := idx; i >= 0; i-- {
+				tail += pd.segments[i]
+			}
+			params.add(n.catchAll.name, tail)
+		}
+		return n.catchAll.route
+	}
+
+	return nil
+}
+
+//findCaseInsensitive behaves like find, but compares static segments case-insensitively and
+//returns the properly-cased canonical path reconstructed from the registered segment values,
+//rather than a *route. Param segments are kept as supplied, since there's no canonical case
+//for them
+func (n *trieNode) findCaseInsensitive(pd *pathDetails, idx int) (string, bool) {
+	if idx < 0 {
+		if n.route != nil {
+			return "", true
+		}
+		return "", false
+	}
+
+	value := pd.segments[idx]
+
+	if n.static != nil {
+		//More than one static child can fold to the same value (e.g. "/Users" and "/users"
+		//both registered); picking the lexicographically smallest match keeps the result
+		//deterministic across calls
+		matchKey, matchTail, found := "", "", false
+
+		for registered, child := range n.static {
+			if !strings.EqualFold(registered, value) {
+				continue
+			}
+
+			tail, ok := child.findCaseInsensitive(pd, idx-1)
+			if !ok {
+				continue
+			}
+
+			if !found || registered < matchKey {
+				matchKey, matchTail, found = registered, tail, true
+			}
+		}
+
+		if found {
+			return matchKey + matchTail, true
+		}
+	}
+
+	if n.param != nil {
+		if tail, ok := n.param.findCaseInsensitive(pd, idx-1); ok {
+			return value + tail, true
+		}
+	}
+
+	if n.catchAll != nil && n.catchAll.route != nil {
+		tail := ""
+		for i := idx; i >= 0; i-- {
+			tail += pd.segments[i]
+		}
+		return tail, true
+	}
+
+	return "", false
+}